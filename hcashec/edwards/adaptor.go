@@ -0,0 +1,126 @@
+// Copyright (c) 2017 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package edwards
+
+import (
+	"crypto/sha512"
+	"errors"
+	"math/big"
+)
+
+// AdaptorSignature is a Schnorr signature that has been "tweaked" by an
+// adaptor point T = t*G for an unknown scalar t. It is not itself a valid
+// signature: it verifies against R = R' - T rather than R', where
+// R' = RPrime is the nonce point actually committed to in the challenge.
+// Publishing the real signature (obtained via Adapt once t is known) both
+// completes the signature and reveals t to anyone who already has the
+// adaptor signature, which is the basis for cross-chain atomic swaps.
+type AdaptorSignature struct {
+	RPrime *PublicKey
+	S      *big.Int
+}
+
+// adaptorChallenge computes c = H(R||P||m), the same SHA-512 challenge
+// construction used elsewhere in this package for plain Schnorr signatures,
+// so that a completed adaptor signature verifies with the standard Verify.
+func adaptorChallenge(r, pub *PublicKey, msg []byte) *big.Int {
+	h := sha512.New()
+	h.Write(r.Serialize())
+	h.Write(pub.Serialize())
+	h.Write(msg)
+
+	// Ed25519 reads the digest as a little-endian integer.
+	digest := h.Sum(nil)
+	for i, j := 0, len(digest)-1; i < j; i, j = i+1, j-1 {
+		digest[i], digest[j] = digest[j], digest[i]
+	}
+	return new(big.Int).SetBytes(digest)
+}
+
+func negatePoint(curve *TwistedEdwardsCurve, p *PublicKey) *PublicKey {
+	negX := new(big.Int).Sub(curve.P, p.GetX())
+	negX.Mod(negX, curve.P)
+	return NewPublicKey(curve, negX, p.GetY())
+}
+
+// AdaptorSign produces an adaptor signature on msg under priv, tweaked by
+// the adaptor point t. nonce is the signer's fresh secret nonce k; the
+// public nonce point R = k*G is offset by t to produce R' = R + T, and the
+// challenge c = H(R'||P||m) is bound to the offset point so the signature
+// cannot be completed without learning the discrete log of T.
+func AdaptorSign(curve *TwistedEdwardsCurve, priv *PrivateKey, nonce []byte, msg []byte, t *PublicKey) (*AdaptorSignature, error) {
+	if t == nil {
+		return nil, errors.New("edwards: adaptor point must not be nil")
+	}
+
+	k := new(big.Int).Mod(new(big.Int).SetBytes(nonce), curve.N)
+	if k.Sign() == 0 {
+		return nil, errors.New("edwards: nonce reduces to zero")
+	}
+
+	rx, ry := curve.ScalarBaseMult(k.Bytes())
+	r := NewPublicKey(curve, rx, ry)
+	rPrime := CombinePubkeys(curve, []*PublicKey{r, t})
+	if rPrime == nil {
+		return nil, errors.New("edwards: failed to offset nonce point by adaptor point")
+	}
+
+	pub := priv.PubKey()
+	c := adaptorChallenge(rPrime, pub, msg)
+	c.Mod(c, curve.N)
+
+	s := new(big.Int).Mul(c, priv.GetD())
+	s.Add(s, k)
+	s.Mod(s, curve.N)
+
+	return &AdaptorSignature{RPrime: rPrime, S: s}, nil
+}
+
+// AdaptorVerify reports whether sig is a valid adaptor signature by pub on
+// msg relative to the adaptor point t, i.e. whether s'*G == (R' - T) + c*P.
+func AdaptorVerify(curve *TwistedEdwardsCurve, pub *PublicKey, msg []byte, sig *AdaptorSignature, t *PublicKey) bool {
+	if sig == nil || sig.RPrime == nil || sig.S == nil || t == nil {
+		return false
+	}
+
+	r := CombinePubkeys(curve, []*PublicKey{sig.RPrime, negatePoint(curve, t)})
+	if r == nil {
+		return false
+	}
+
+	c := adaptorChallenge(sig.RPrime, pub, msg)
+	c.Mod(c, curve.N)
+
+	sx, sy := curve.ScalarBaseMult(sig.S.Bytes())
+	cx, cy := curve.ScalarMult(pub.GetX(), pub.GetY(), c.Bytes())
+	rhs := CombinePubkeys(curve, []*PublicKey{r, NewPublicKey(curve, cx, cy)})
+	if rhs == nil {
+		return false
+	}
+
+	return sx.Cmp(rhs.GetX()) == 0 && sy.Cmp(rhs.GetY()) == 0
+}
+
+// Adapt completes sig with the adaptor secret t, producing a standard
+// Ed25519 Schnorr signature over R' that verifies with Verify.
+func Adapt(sig *AdaptorSignature, curve *TwistedEdwardsCurve, t *big.Int) *Signature {
+	s := new(big.Int).Add(sig.S, t)
+	s.Mod(s, curve.N)
+
+	rEnc := EncodedBytesToBigInt(copyBytes(sig.RPrime.Serialize()))
+	return NewSignature(rEnc, s)
+}
+
+// Extract recovers the adaptor secret t from a completed signature and the
+// adaptor signature it was derived from, i.e. t = s - s' (mod N).
+func Extract(curve *TwistedEdwardsCurve, finalSig *Signature, adaptorSig *AdaptorSignature) (*big.Int, error) {
+	if finalSig == nil || adaptorSig == nil {
+		return nil, errors.New("edwards: finalSig and adaptorSig must not be nil")
+	}
+
+	t := new(big.Int).Sub(finalSig.GetS(), adaptorSig.S)
+	t.Mod(t, curve.N)
+	return t, nil
+}
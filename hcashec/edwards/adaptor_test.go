@@ -0,0 +1,125 @@
+// Copyright (c) 2017 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package edwards
+
+import (
+	"crypto/elliptic"
+	"encoding/hex"
+	"math/big"
+	"testing"
+)
+
+// TestAdaptorSignature exercises the full atomic-swap flow: an adaptor
+// signature is produced against a fixed adaptor point T and passes
+// AdaptorVerify, and once adapted it both verifies normally and reveals
+// t = dlog(T).
+func TestAdaptorSignature(t *testing.T) {
+	curve := new(TwistedEdwardsCurve)
+	curve.InitParam25519()
+
+	msg, _ := hex.DecodeString(
+		"d04b98f48e8f8bcc15c6ae5ac050801cd6dcfd428fb5f9e65c4e16e7807340fa")
+
+	privScalar, _ := hex.DecodeString(
+		"0706050403020100ffeeddccbbaa99887766554433221100ffeeddccbbaa9988")
+	priv, pub, err := PrivKeyFromScalar(curve, privScalar)
+	if err != nil {
+		t.Fatalf("PrivKeyFromScalar: %v", err)
+	}
+
+	secretScalar, _ := hex.DecodeString(
+		"1011121314151617202122232425262730313233343536404142434445464a")
+	secretPriv, adaptorPoint, err := PrivKeyFromScalar(curve, secretScalar)
+	if err != nil {
+		t.Fatalf("PrivKeyFromScalar(adaptor secret): %v", err)
+	}
+
+	nonce, _ := hex.DecodeString(
+		"2122232425262730313233343536404142434445464a50515253545556575a")
+
+	adaptorSig, err := AdaptorSign(curve, priv, nonce, msg, adaptorPoint)
+	if err != nil {
+		t.Fatalf("AdaptorSign: %v", err)
+	}
+
+	if !AdaptorVerify(curve, pub, msg, adaptorSig, adaptorPoint) {
+		t.Fatal("AdaptorVerify rejected a valid adaptor signature")
+	}
+
+	finalSig := Adapt(adaptorSig, curve, secretPriv.GetD())
+	if !Verify(pub, msg, finalSig.GetR(), finalSig.GetS()) {
+		t.Fatal("completed adaptor signature did not verify")
+	}
+
+	extracted, err := Extract(curve, finalSig, adaptorSig)
+	if err != nil {
+		t.Fatalf("Extract: %v", err)
+	}
+	if extracted.Cmp(secretPriv.GetD()) != 0 {
+		t.Fatalf("Extract returned %s, want %s",
+			extracted.String(), secretPriv.GetD().String())
+	}
+}
+
+// TestDLEQProof checks a DLEQ proof of equal discrete logs verifies when
+// proved honestly and fails when the claimed points don't match.
+func TestDLEQProof(t *testing.T) {
+	curve := new(TwistedEdwardsCurve)
+	curve.InitParam25519()
+
+	x := big.NewInt(123456789)
+	k := big.NewInt(987654321)
+
+	xG1, xG2, proof, err := DLEQProve(curve, curve, x, k)
+	if err != nil {
+		t.Fatalf("DLEQProve: %v", err)
+	}
+
+	if !DLEQVerify(curve, curve, xG1, xG2, proof) {
+		t.Fatal("DLEQVerify rejected an honestly generated proof")
+	}
+
+	wrongX := Point{X: new(big.Int).Add(xG1.X, big.NewInt(1)), Y: xG1.Y}
+	if DLEQVerify(curve, curve, wrongX, xG2, proof) {
+		t.Fatal("DLEQVerify accepted a proof against a mismatched point")
+	}
+}
+
+// TestDLEQProofCrossCurve checks a DLEQ proof between two curves with
+// genuinely different group orders (Ed25519's ~2^252 and P-256's ~2^256),
+// which is the actual use case DLEQProof is for: tying together a
+// signature on this package's TwistedEdwardsCurve and a signature on an
+// entirely different curve. Using the same curve on both sides (as
+// TestDLEQProof does) can't catch a response that was reduced modulo the
+// wrong curve's order, since both orders are equal in that case.
+func TestDLEQProofCrossCurve(t *testing.T) {
+	curve1 := new(TwistedEdwardsCurve)
+	curve1.InitParam25519()
+	curve2 := elliptic.P256()
+
+	x := big.NewInt(123456789)
+	k := big.NewInt(987654321)
+
+	xG1, xG2, proof, err := DLEQProve(curve1, curve2, x, k)
+	if err != nil {
+		t.Fatalf("DLEQProve: %v", err)
+	}
+
+	if !DLEQVerify(curve1, curve2, xG1, xG2, proof) {
+		t.Fatal("DLEQVerify rejected an honestly generated cross-curve proof")
+	}
+
+	wrongX := Point{X: new(big.Int).Add(xG1.X, big.NewInt(1)), Y: xG1.Y}
+	if DLEQVerify(curve1, curve2, wrongX, xG2, proof) {
+		t.Fatal("DLEQVerify accepted a cross-curve proof against a mismatched point")
+	}
+
+	// A scalar at or above the smaller curve's order is ambiguous across
+	// the two curves and must be rejected outright.
+	tooLarge := new(big.Int).Set(curve1.N)
+	if _, _, _, err := DLEQProve(curve1, curve2, tooLarge, k); err == nil {
+		t.Fatal("DLEQProve accepted a scalar at or above the smaller curve's group order")
+	}
+}
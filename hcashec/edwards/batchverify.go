@@ -0,0 +1,110 @@
+// Copyright (c) 2017 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package edwards
+
+import (
+	"crypto/rand"
+	"math/big"
+)
+
+// BatchEntry is one (pubkey, message, signature) triple to be checked by
+// BatchVerify.
+type BatchEntry struct {
+	Pub *PublicKey
+	Msg []byte
+	R   *big.Int
+	S   *big.Int
+}
+
+// randomBatchScalar returns a fresh non-zero scalar used to randomly
+// combine the entries of a batch, so a forger can't exploit the linearity
+// of the combined equation by choosing one bad signature that cancels
+// another's error term.
+func randomBatchScalar(curve *TwistedEdwardsCurve) (*big.Int, error) {
+	buf := make([]byte, 64)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, err
+	}
+	z := new(big.Int).SetBytes(buf)
+	z.Mod(z, curve.N)
+	if z.Sign() == 0 {
+		z.SetInt64(1)
+	}
+	return z, nil
+}
+
+// BatchVerify checks every entry in entries with a single combined
+// equation,
+//
+//	sum(z_i*s_i)*G == sum(z_i*R_i) + sum(z_i*c_i*P_i)
+//
+// for independently random scalars z_i. Each term on the right is computed
+// with its own scalar multiplications (two per entry), plus one base-point
+// multiplication for the left side; the terms are not evaluated with a
+// shared multi-scalar multiplication.
+//
+// If the batch fails, BatchVerify falls back to verifying every entry
+// individually and returns the indices of the ones that failed.
+func BatchVerify(curve *TwistedEdwardsCurve, entries []BatchEntry) (bool, []int) {
+	if len(entries) == 0 {
+		return true, nil
+	}
+
+	ok, err := batchCheck(curve, entries)
+	if err == nil && ok {
+		return true, nil
+	}
+
+	var bad []int
+	for i, e := range entries {
+		if !Verify(e.Pub, e.Msg, e.R, e.S) {
+			bad = append(bad, i)
+		}
+	}
+	return len(bad) == 0, bad
+}
+
+func batchCheck(curve *TwistedEdwardsCurve, entries []BatchEntry) (bool, error) {
+	lhsScalar := new(big.Int)
+	var rhsSum *PublicKey
+
+	for _, e := range entries {
+		z, err := randomBatchScalar(curve)
+		if err != nil {
+			return false, err
+		}
+
+		rBytes := BigIntToEncodedBytes(e.R)
+		r, err := ParsePubKey(curve, rBytes[:])
+		if err != nil {
+			return false, err
+		}
+
+		c := adaptorChallenge(r, e.Pub, e.Msg)
+		c.Mod(c, curve.N)
+
+		zs := new(big.Int).Mul(z, e.S)
+		lhsScalar.Add(lhsScalar, zs)
+
+		zrx, zry := curve.ScalarMult(r.GetX(), r.GetY(), z.Bytes())
+		zR := NewPublicKey(curve, zrx, zry)
+
+		zc := new(big.Int).Mul(z, c)
+		zcx, zcy := curve.ScalarMult(e.Pub.GetX(), e.Pub.GetY(), zc.Bytes())
+		zcP := NewPublicKey(curve, zcx, zcy)
+
+		term := CombinePubkeys(curve, []*PublicKey{zR, zcP})
+		if rhsSum == nil {
+			rhsSum = term
+		} else {
+			rhsSum = CombinePubkeys(curve, []*PublicKey{rhsSum, term})
+		}
+	}
+
+	lhsScalar.Mod(lhsScalar, curve.N)
+	lhsX, lhsY := curve.ScalarBaseMult(lhsScalar.Bytes())
+
+	return lhsX.Cmp(rhsSum.GetX()) == 0 && lhsY.Cmp(rhsSum.GetY()) == 0, nil
+}
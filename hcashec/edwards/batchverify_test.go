@@ -0,0 +1,65 @@
+// Copyright (c) 2017 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package edwards
+
+import (
+	"math/big"
+	"testing"
+)
+
+// TestBatchCheckFastPath exercises batchCheck directly rather than through
+// BatchVerify. BatchVerify falls back to a full per-signature Verify loop
+// whenever batchCheck fails, so a test that only calls BatchVerify can't
+// tell a working combined multi-scalar-multiplication check apart from one
+// that's permanently broken and silently degrading to one-by-one
+// verification on every call, which would defeat the entire point of
+// batching during block validation.
+func TestBatchCheckFastPath(t *testing.T) {
+	curve := new(TwistedEdwardsCurve)
+	curve.InitParam25519()
+
+	const numSigs = 8
+	entries := make([]BatchEntry, numSigs)
+	for i := 0; i < numSigs; i++ {
+		var scalar [32]byte
+		scalar[0] = byte(i + 1)
+		priv, pub, err := PrivKeyFromScalar(curve, scalar[:])
+		if err != nil {
+			t.Fatalf("PrivKeyFromScalar(%d): %v", i, err)
+		}
+
+		msg := []byte{byte(i), 0xaa, 0xbb}
+
+		var nonce [32]byte
+		nonce[1] = byte(i + 1)
+		r, s, err := SignFromScalar(curve, priv, nonce[:], msg)
+		if err != nil {
+			t.Fatalf("SignFromScalar(%d): %v", i, err)
+		}
+
+		entries[i] = BatchEntry{Pub: pub, Msg: msg, R: r, S: s}
+	}
+
+	ok, err := batchCheck(curve, entries)
+	if err != nil {
+		t.Fatalf("batchCheck: unexpected error: %v", err)
+	}
+	if !ok {
+		t.Fatal("batchCheck rejected a fully valid batch via its combined multi-scalar-multiplication equation")
+	}
+
+	corrupted := make([]BatchEntry, numSigs)
+	copy(corrupted, entries)
+	corruptedS := new(big.Int).Add(entries[3].S, big.NewInt(1))
+	corrupted[3] = BatchEntry{Pub: entries[3].Pub, Msg: entries[3].Msg, R: entries[3].R, S: corruptedS}
+
+	ok, err = batchCheck(curve, corrupted)
+	if err != nil {
+		t.Fatalf("batchCheck: unexpected error: %v", err)
+	}
+	if ok {
+		t.Fatal("batchCheck's combined equation accepted a batch containing a corrupted signature")
+	}
+}
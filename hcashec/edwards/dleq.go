@@ -0,0 +1,124 @@
+// Copyright (c) 2017 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package edwards
+
+import (
+	"crypto/elliptic"
+	"crypto/sha512"
+	"errors"
+	"math/big"
+)
+
+// Point is a generic affine curve point. DLEQProof operates across two
+// possibly-different elliptic.Curve implementations (for example Ed25519
+// and secp256k1), so its points are not tied to this package's PublicKey
+// type.
+type Point struct {
+	X, Y *big.Int
+}
+
+// DLEQProof is a Chaum-Pedersen proof that the same scalar x is the
+// discrete log of a point xG1 on curve1 and of a point xG2 on curve2. It
+// lets two parties on different curves (e.g. an Ed25519 HC key and a
+// secp256k1 Monero-style key) agree that a value they each see tweaked by x
+// is the same x, without revealing it, which is the building block for
+// cross-chain atomic swaps that don't share a common signature scheme.
+type DLEQProof struct {
+	R1 Point
+	R2 Point
+	S  *big.Int
+}
+
+func dleqChallenge(curve1, curve2 elliptic.Curve, xG1, xG2, r1, r2 Point) *big.Int {
+	p1 := curve1.Params()
+	p2 := curve2.Params()
+
+	h := sha512.New()
+	for _, v := range []*big.Int{
+		p1.Gx, p1.Gy, p2.Gx, p2.Gy,
+		xG1.X, xG1.Y, xG2.X, xG2.Y,
+		r1.X, r1.Y, r2.X, r2.Y,
+	} {
+		h.Write(v.Bytes())
+	}
+	return new(big.Int).SetBytes(h.Sum(nil))
+}
+
+// DLEQProve proves that x is the discrete log of both xG1 (on curve1) and
+// xG2 (on curve2), using k as the proof's fresh random nonce. It returns
+// the two public points so the verifier does not need to compute them
+// independently.
+func DLEQProve(curve1, curve2 elliptic.Curve, x, k *big.Int) (xG1, xG2 Point, proof *DLEQProof, err error) {
+	if x == nil || x.Sign() == 0 {
+		return Point{}, Point{}, nil, errors.New("edwards: DLEQ scalar must be non-zero")
+	}
+	if k == nil || k.Sign() == 0 {
+		return Point{}, Point{}, nil, errors.New("edwards: DLEQ nonce must be non-zero")
+	}
+
+	// x (and the nonce k) must be unambiguous as a discrete log on both
+	// curves at once, which only holds if it's smaller than either curve's
+	// group order: otherwise x and x mod N1 (or x mod N2) would be
+	// indistinguishable on the curve with the smaller order while still
+	// differing on the other, breaking the "same x on both curves" claim
+	// the proof is supposed to make.
+	n1 := curve1.Params().N
+	n2 := curve2.Params().N
+	minN := n1
+	if n2.Cmp(minN) < 0 {
+		minN = n2
+	}
+	if x.Cmp(minN) >= 0 {
+		return Point{}, Point{}, nil, errors.New("edwards: DLEQ scalar must be below the smaller curve's group order")
+	}
+	if k.Cmp(minN) >= 0 {
+		return Point{}, Point{}, nil, errors.New("edwards: DLEQ nonce must be below the smaller curve's group order")
+	}
+
+	xg1x, xg1y := curve1.ScalarBaseMult(x.Bytes())
+	xg2x, xg2y := curve2.ScalarBaseMult(x.Bytes())
+	xG1 = Point{xg1x, xg1y}
+	xG2 = Point{xg2x, xg2y}
+
+	r1x, r1y := curve1.ScalarBaseMult(k.Bytes())
+	r2x, r2y := curve2.ScalarBaseMult(k.Bytes())
+	r1 := Point{r1x, r1y}
+	r2 := Point{r2x, r2y}
+
+	e := dleqChallenge(curve1, curve2, xG1, xG2, r1, r2)
+
+	// s = k + e*x is kept as a plain integer, not reduced modulo either
+	// curve's order: n*P is automatically congruent to (n mod ord(P))*P in
+	// any cyclic group, so no explicit reduction is needed, and reducing
+	// by one curve's order (as a prior version of this code did) would
+	// silently corrupt the check on a curve with a different order.
+	s := new(big.Int).Mul(e, x)
+	s.Add(s, k)
+
+	return xG1, xG2, &DLEQProof{R1: r1, R2: r2, S: s}, nil
+}
+
+// DLEQVerify checks a DLEQProof for the claimed points xG1 (on curve1) and
+// xG2 (on curve2), independently verifying s*G1 == R1 + e*xG1 and
+// s*G2 == R2 + e*xG2 for the same challenge e and response s.
+func DLEQVerify(curve1, curve2 elliptic.Curve, xG1, xG2 Point, proof *DLEQProof) bool {
+	if proof == nil || proof.S == nil {
+		return false
+	}
+
+	e := dleqChallenge(curve1, curve2, xG1, xG2, proof.R1, proof.R2)
+
+	sg1x, sg1y := curve1.ScalarBaseMult(proof.S.Bytes())
+	exg1x, exg1y := curve1.ScalarMult(xG1.X, xG1.Y, e.Bytes())
+	rhs1x, rhs1y := curve1.Add(proof.R1.X, proof.R1.Y, exg1x, exg1y)
+	if sg1x.Cmp(rhs1x) != 0 || sg1y.Cmp(rhs1y) != 0 {
+		return false
+	}
+
+	sg2x, sg2y := curve2.ScalarBaseMult(proof.S.Bytes())
+	exg2x, exg2y := curve2.ScalarMult(xG2.X, xG2.Y, e.Bytes())
+	rhs2x, rhs2y := curve2.Add(proof.R2.X, proof.R2.Y, exg2x, exg2y)
+	return sg2x.Cmp(rhs2x) == 0 && sg2y.Cmp(rhs2y) == 0
+}
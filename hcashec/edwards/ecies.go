@@ -0,0 +1,144 @@
+// Copyright (c) 2017 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package edwards
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"errors"
+	"io"
+
+	"golang.org/x/crypto/hkdf"
+)
+
+// hkdfInfo domain-separates the key material this package derives from an
+// ECDH shared secret from any other use of the same secret elsewhere.
+const hkdfInfo = "hcashec/edwards ECIES"
+
+// GenerateSharedSecret performs an X25519-style Diffie-Hellman exchange,
+// computing priv.D * pub and returning the SHA-512-truncated x-coordinate
+// of the resulting point as a 32-byte shared secret.
+//
+// pub is first multiplied by the curve's cofactor to clear any small-order
+// component before the private scalar is applied. Ed25519's group has
+// cofactor 8, so a point pub crafted to lie in one of the small subgroups
+// (as Decrypt's caller-controlled ephemeral key could be) would otherwise
+// let an attacker learn priv's low-order bits by observing how the shared
+// secret varies across repeated queries with different small-order points
+// (small-subgroup confinement); multiplying by the cofactor first maps
+// every such component to the identity, so it can no longer leak anything.
+func GenerateSharedSecret(priv *PrivateKey, pub *PublicKey) []byte {
+	curve := pub.GetCurve()
+
+	cx, cy := curve.ScalarMult(pub.GetX(), pub.GetY(), curve.H.Bytes())
+	x, _ := curve.ScalarMult(cx, cy, priv.GetD().Bytes())
+
+	digest := sha512.Sum512(BigIntToEncodedBytes(x)[:])
+	return digest[:32]
+}
+
+// deriveKeys expands an ECDH shared secret into a 256-bit AES-CTR
+// encryption key and a 256-bit HMAC-SHA256 authentication key using HKDF.
+func deriveKeys(secret []byte) (encKey, macKey []byte, err error) {
+	kdf := hkdf.New(sha256.New, secret, nil, []byte(hkdfInfo))
+
+	keys := make([]byte, 64)
+	if _, err := io.ReadFull(kdf, keys); err != nil {
+		return nil, nil, err
+	}
+	return keys[:32], keys[32:], nil
+}
+
+// Encrypt implements ECIES: it generates an ephemeral keypair, derives an
+// AES-256-CTR key and an HMAC-SHA256 key from the ECDH shared secret
+// between the ephemeral key and pub, and returns
+// ephPub(32) || iv(16) || ciphertext || hmac(32).
+func Encrypt(pub *PublicKey, msg []byte) ([]byte, error) {
+	curve := pub.GetCurve()
+
+	var ephScalar [32]byte
+	if _, err := rand.Read(ephScalar[:]); err != nil {
+		return nil, err
+	}
+	ephPriv, ephPub, err := PrivKeyFromScalar(curve, ephScalar[:])
+	if err != nil {
+		return nil, err
+	}
+
+	shared := GenerateSharedSecret(ephPriv, pub)
+	encKey, macKey, err := deriveKeys(shared)
+	if err != nil {
+		return nil, err
+	}
+
+	iv := make([]byte, aes.BlockSize)
+	if _, err := rand.Read(iv); err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(encKey)
+	if err != nil {
+		return nil, err
+	}
+	ciphertext := make([]byte, len(msg))
+	cipher.NewCTR(block, iv).XORKeyStream(ciphertext, msg)
+
+	out := make([]byte, 0, 32+aes.BlockSize+len(ciphertext)+sha256.Size)
+	out = append(out, ephPub.Serialize()...)
+	out = append(out, iv...)
+	out = append(out, ciphertext...)
+
+	mac := hmac.New(sha256.New, macKey)
+	mac.Write(out)
+	out = mac.Sum(out)
+
+	return out, nil
+}
+
+// Decrypt reverses Encrypt, deriving the same ECDH shared secret from priv
+// and the ephemeral public key embedded in ciphertext, verifying the HMAC
+// tag before decrypting.
+func Decrypt(priv *PrivateKey, ciphertext []byte) ([]byte, error) {
+	const overhead = 32 + aes.BlockSize + sha256.Size
+	if len(ciphertext) < overhead {
+		return nil, errors.New("edwards: ciphertext too short to be a valid ECIES payload")
+	}
+
+	curve := priv.PubKey().GetCurve()
+
+	ephPub, err := ParsePubKey(curve, ciphertext[:32])
+	if err != nil {
+		return nil, err
+	}
+	iv := ciphertext[32 : 32+aes.BlockSize]
+	body := ciphertext[:len(ciphertext)-sha256.Size]
+	tag := ciphertext[len(ciphertext)-sha256.Size:]
+	encrypted := ciphertext[32+aes.BlockSize : len(ciphertext)-sha256.Size]
+
+	shared := GenerateSharedSecret(priv, ephPub)
+	encKey, macKey, err := deriveKeys(shared)
+	if err != nil {
+		return nil, err
+	}
+
+	mac := hmac.New(sha256.New, macKey)
+	mac.Write(body)
+	if !hmac.Equal(tag, mac.Sum(nil)) {
+		return nil, errors.New("edwards: invalid ECIES authentication tag")
+	}
+
+	block, err := aes.NewCipher(encKey)
+	if err != nil {
+		return nil, err
+	}
+	plaintext := make([]byte, len(encrypted))
+	cipher.NewCTR(block, iv).XORKeyStream(plaintext, encrypted)
+
+	return plaintext, nil
+}
@@ -0,0 +1,111 @@
+// Copyright (c) 2017 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package edwards
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+)
+
+// TestGenerateSharedSecretSymmetric checks that both sides of an ECDH
+// exchange derive the same shared secret.
+func TestGenerateSharedSecretSymmetric(t *testing.T) {
+	curve := new(TwistedEdwardsCurve)
+	curve.InitParam25519()
+
+	var aScalar, bScalar [32]byte
+	aScalar[0] = 0x11
+	bScalar[0] = 0x22
+
+	privA, pubA, err := PrivKeyFromScalar(curve, aScalar[:])
+	if err != nil {
+		t.Fatalf("PrivKeyFromScalar(a): %v", err)
+	}
+	privB, pubB, err := PrivKeyFromScalar(curve, bScalar[:])
+	if err != nil {
+		t.Fatalf("PrivKeyFromScalar(b): %v", err)
+	}
+
+	secretA := GenerateSharedSecret(privA, pubB)
+	secretB := GenerateSharedSecret(privB, pubA)
+
+	if !bytes.Equal(secretA, secretB) {
+		t.Fatalf("shared secrets differ: %x vs %x", secretA, secretB)
+	}
+}
+
+// TestGenerateSharedSecretCofactorCleared checks that perturbing a peer's
+// public key by a small-order point doesn't change the derived shared
+// secret. Without cofactor clearing, a malicious peer could choose
+// ephemeral keys that differ only by small-order components and use the
+// resulting variation in Decrypt's shared secret to mount a small-subgroup
+// confinement attack, leaking priv's low-order bits.
+func TestGenerateSharedSecretCofactorCleared(t *testing.T) {
+	curve := new(TwistedEdwardsCurve)
+	curve.InitParam25519()
+
+	var scalar [32]byte
+	scalar[0] = 0x44
+	priv, pub, err := PrivKeyFromScalar(curve, scalar[:])
+	if err != nil {
+		t.Fatalf("PrivKeyFromScalar: %v", err)
+	}
+
+	// (0, p-1) is the order-2 point present in the full (cofactor-8) group
+	// of every twisted Edwards curve: at x=0 the curve equation reduces to
+	// y^2 = 1 (mod p), and y = p-1 (i.e. -1) is the other root besides the
+	// identity's y = 1.
+	torsionY := new(big.Int).Sub(curve.P, big.NewInt(1))
+	torsion := NewPublicKey(curve, big.NewInt(0), torsionY)
+
+	cx, cy := curve.Add(pub.GetX(), pub.GetY(), torsion.GetX(), torsion.GetY())
+	confined := NewPublicKey(curve, cx, cy)
+
+	secret := GenerateSharedSecret(priv, pub)
+	confinedSecret := GenerateSharedSecret(priv, confined)
+
+	if !bytes.Equal(secret, confinedSecret) {
+		t.Fatal("GenerateSharedSecret is not cofactor-cleared: the shared " +
+			"secret changed when the peer key was perturbed by a small-order " +
+			"point, which would leak private key bits to a malicious peer")
+	}
+}
+
+// TestEncryptDecrypt checks the ECIES round trip and that tampering with
+// the ciphertext is detected via the HMAC tag.
+func TestEncryptDecrypt(t *testing.T) {
+	curve := new(TwistedEdwardsCurve)
+	curve.InitParam25519()
+
+	var scalar [32]byte
+	scalar[0] = 0x33
+	priv, pub, err := PrivKeyFromScalar(curve, scalar[:])
+	if err != nil {
+		t.Fatalf("PrivKeyFromScalar: %v", err)
+	}
+
+	msg := []byte("the quick brown fox jumps over the lazy dog")
+
+	ciphertext, err := Encrypt(pub, msg)
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+
+	plaintext, err := Decrypt(priv, ciphertext)
+	if err != nil {
+		t.Fatalf("Decrypt: %v", err)
+	}
+	if !bytes.Equal(plaintext, msg) {
+		t.Fatalf("Decrypt returned %q, want %q", plaintext, msg)
+	}
+
+	tampered := make([]byte, len(ciphertext))
+	copy(tampered, ciphertext)
+	tampered[len(tampered)-1] ^= 0xff
+	if _, err := Decrypt(priv, tampered); err == nil {
+		t.Fatal("Decrypt accepted a tampered ciphertext")
+	}
+}
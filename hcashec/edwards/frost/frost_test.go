@@ -0,0 +1,106 @@
+// Copyright (c) 2017 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package frost
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/tiaotiao00/hcashd/hcashec/edwards"
+)
+
+// TestFROSTThreeOfFive runs a full dealer-less DKG and a threshold signing
+// session for a 3-of-5 group, using only 3 of the 5 participants to
+// produce the signature, and checks the result verifies as a standard
+// Ed25519 Schnorr signature against the group's public key.
+func TestFROSTThreeOfFive(t *testing.T) {
+	const (
+		n         = 5
+		threshold = 3
+	)
+
+	curve := new(edwards.TwistedEdwardsCurve)
+	curve.InitParam25519()
+
+	msg, _ := hex.DecodeString(
+		"d04b98f48e8f8bcc15c6ae5ac050801cd6dcfd428fb5f9e65c4e16e7807340fa")
+
+	ids := make([]int, n)
+	for i := range ids {
+		ids[i] = i + 1
+	}
+
+	// Dealer-less DKG: every participant deals shares of its own
+	// polynomial to every other participant, verifying each share it
+	// receives against the dealer's Pedersen commitments.
+	polys := make([]*Polynomial, n)
+	commitments := make([][]*edwards.PublicKey, n)
+	for i := range ids {
+		poly, err := GeneratePolynomial(curve, threshold, rand.Reader)
+		if err != nil {
+			t.Fatalf("GeneratePolynomial(%d): %v", i, err)
+		}
+		polys[i] = poly
+		commitments[i] = poly.Commitments(curve)
+	}
+
+	shares := make([]*big.Int, n)
+	for recipient := range ids {
+		received := make([]*big.Int, n)
+		for dealer := range ids {
+			share := polys[dealer].ShareFor(curve, ids[recipient])
+			if !VerifyShare(curve, ids[recipient], share, commitments[dealer]) {
+				t.Fatalf("VerifyShare: share from dealer %d to %d failed verification",
+					ids[dealer], ids[recipient])
+			}
+			received[dealer] = share
+		}
+		shares[recipient] = AggregateShares(curve, received)
+	}
+
+	constantCommitments := make([]*edwards.PublicKey, n)
+	for i := range ids {
+		constantCommitments[i] = commitments[i][0]
+	}
+	groupPub := GroupPublicKey(curve, constantCommitments)
+
+	// Threshold signing: only the first `threshold` participants sign.
+	signerIdx := []int{0, 1, 2}
+	signerIDs := make([]int, len(signerIdx))
+	for i, idx := range signerIdx {
+		signerIDs[i] = ids[idx]
+	}
+
+	allCommitments := make([]*NonceCommitment, len(signerIdx))
+	nonces := make([]*SignerNonces, len(signerIdx))
+	for i, idx := range signerIdx {
+		sn, nc, err := GenerateNonces(curve, ids[idx], rand.Reader)
+		if err != nil {
+			t.Fatalf("GenerateNonces(%d): %v", ids[idx], err)
+		}
+		nonces[i] = sn
+		allCommitments[i] = nc
+	}
+
+	partials := make([]*big.Int, len(signerIdx))
+	for i, idx := range signerIdx {
+		z, err := Sign(curve, ids[idx], shares[idx], nonces[i], msg, groupPub, allCommitments, signerIDs)
+		if err != nil {
+			t.Fatalf("Sign(%d): %v", ids[idx], err)
+		}
+		partials[i] = z
+	}
+
+	sig, err := CombineSignatures(curve, msg, allCommitments, partials)
+	if err != nil {
+		t.Fatalf("CombineSignatures: %v", err)
+	}
+
+	if !edwards.Verify(groupPub, msg, sig.GetR(), sig.GetS()) {
+		t.Fatal("failed to verify the combined FROST threshold signature")
+	}
+}
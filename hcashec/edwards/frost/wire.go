@@ -0,0 +1,67 @@
+// Copyright (c) 2017 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package frost
+
+import (
+	"encoding/binary"
+	"errors"
+	"math/big"
+
+	"github.com/tiaotiao00/hcashd/hcashec/edwards"
+)
+
+// shareWireSize is the encoded size of a Share: a 4-byte participant id
+// followed by a 32-byte big-endian scalar.
+const shareWireSize = 4 + 32
+
+// Share is a participant's long-term secret share of the group key,
+// produced by aggregating the VSS shares it received from every dealer
+// during key generation.
+type Share struct {
+	ID    int
+	Value *big.Int
+}
+
+// Serialize encodes a Share for transport between participants.
+func (s *Share) Serialize() []byte {
+	buf := make([]byte, shareWireSize)
+	binary.BigEndian.PutUint32(buf[:4], uint32(s.ID))
+
+	v := s.Value.Bytes()
+	copy(buf[4+32-len(v):], v)
+	return buf
+}
+
+// DeserializeShare decodes a Share encoded by Serialize.
+func DeserializeShare(data []byte) (*Share, error) {
+	if len(data) != shareWireSize {
+		return nil, errors.New("frost: invalid share encoding")
+	}
+
+	id := binary.BigEndian.Uint32(data[:4])
+	value := new(big.Int).SetBytes(data[4:])
+	return &Share{ID: int(id), Value: value}, nil
+}
+
+// DeserializeNonceCommitment decodes a NonceCommitment encoded by
+// NonceCommitment.Serialize.
+func DeserializeNonceCommitment(curve *edwards.TwistedEdwardsCurve, data []byte) (*NonceCommitment, error) {
+	if len(data) != 4+2*pubKeySize {
+		return nil, errors.New("frost: invalid nonce commitment encoding")
+	}
+
+	id := binary.BigEndian.Uint32(data[:4])
+
+	d, err := edwards.ParsePubKey(curve, data[4:4+pubKeySize])
+	if err != nil {
+		return nil, err
+	}
+	e, err := edwards.ParsePubKey(curve, data[4+pubKeySize:])
+	if err != nil {
+		return nil, err
+	}
+
+	return &NonceCommitment{ID: int(id), D: d, E: e}, nil
+}
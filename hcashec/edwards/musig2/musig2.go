@@ -0,0 +1,255 @@
+// Copyright (c) 2017 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+// Package musig2 implements the MuSig2 two-round Schnorr multi-signature
+// scheme (Nick, Ruffing, Seurin) on top of the hcashec/edwards Ed25519
+// Schnorr primitives.
+//
+// The plain n-of-n scheme in the parent edwards package combines public
+// keys by naive addition, which is vulnerable to Wagner-style rogue-key
+// attacks: a dishonest signer can choose its own key as a function of the
+// honest signers' keys so that it alone controls the aggregate. MuSig2
+// closes that hole by binding every signer's key into the aggregate with a
+// coefficient derived from the full key set (KeyAgg), and every signer's
+// nonce pair into the final nonce with a coefficient derived from the
+// aggregated nonces (the b coefficient in Sign), so no signer can choose
+// its contribution after seeing anyone else's.
+package musig2
+
+import (
+	"bytes"
+	"crypto/sha512"
+	"errors"
+	"math/big"
+	"sort"
+
+	"github.com/tiaotiao00/hcashd/hcashec/edwards"
+)
+
+// Domain tags used to separate the internal hashes used during key
+// aggregation and nonce binding, following the tagged-hash construction
+// popularized by BIP340 and the MuSig2 paper:
+// SHA512(tag-hash || tag-hash || data...). The final signing challenge is
+// deliberately not among these: see sessionChallenge.
+const (
+	tagKeyAggList = "MuSig2/KeyAgg list"
+	tagKeyAggCoef = "MuSig2/KeyAgg coefficient"
+	tagNonceCoef  = "MuSig2/noncecoef"
+)
+
+// taggedHash returns H_tag(data...) reduced modulo the group order, since
+// every use of it in this package yields a scalar.
+func taggedHash(curve *edwards.TwistedEdwardsCurve, tag string, data ...[]byte) *big.Int {
+	tagHash := sha512.Sum512([]byte(tag))
+
+	h := sha512.New()
+	h.Write(tagHash[:])
+	h.Write(tagHash[:])
+	for _, d := range data {
+		h.Write(d)
+	}
+
+	e := new(big.Int).SetBytes(h.Sum(nil))
+	return e.Mod(e, curve.N)
+}
+
+// AggregatedKey is the result of running KeyAgg over a set of signers'
+// public keys: the aggregated key Q used as the effective signing key, and
+// the per-signer coefficients a_i needed to build and verify partial
+// signatures against Q.
+type AggregatedKey struct {
+	Q       *edwards.PublicKey
+	Pubkeys []*edwards.PublicKey
+	Coefs   []*big.Int
+}
+
+// CoefFor returns the KeyAgg coefficient a_i for pub, or an error if pub
+// was not part of the aggregated set.
+func (ak *AggregatedKey) CoefFor(pub *edwards.PublicKey) (*big.Int, error) {
+	serPub := pub.Serialize()
+	for i, p := range ak.Pubkeys {
+		if bytes.Equal(p.Serialize(), serPub) {
+			return ak.Coefs[i], nil
+		}
+	}
+	return nil, errors.New("musig2: public key is not part of the aggregated set")
+}
+
+func scalarMultPoint(curve *edwards.TwistedEdwardsCurve, p *edwards.PublicKey, a *big.Int) *edwards.PublicKey {
+	x, y := curve.ScalarMult(p.GetX(), p.GetY(), a.Bytes())
+	return edwards.NewPublicKey(curve, x, y)
+}
+
+func addPoints(curve *edwards.TwistedEdwardsCurve, points ...*edwards.PublicKey) *edwards.PublicKey {
+	return edwards.CombinePubkeys(curve, points)
+}
+
+// KeyAgg computes the MuSig2 aggregated key for pubkeys. The keys are
+// sorted lexicographically by their serialized encoding before the
+// key-aggregation list hash L is computed, so KeyAgg is independent of the
+// order the caller supplies them in.
+func KeyAgg(curve *edwards.TwistedEdwardsCurve, pubkeys []*edwards.PublicKey) (*AggregatedKey, error) {
+	if len(pubkeys) == 0 {
+		return nil, errors.New("musig2: KeyAgg requires at least one public key")
+	}
+
+	sorted := make([]*edwards.PublicKey, len(pubkeys))
+	copy(sorted, pubkeys)
+	sort.Slice(sorted, func(i, j int) bool {
+		return bytes.Compare(sorted[i].Serialize(), sorted[j].Serialize()) < 0
+	})
+
+	serialized := make([][]byte, len(sorted))
+	for i, p := range sorted {
+		serialized[i] = p.Serialize()
+	}
+	l := taggedHash(curve, tagKeyAggList, serialized...)
+	lBytes := l.Bytes()
+
+	coefs := make([]*big.Int, len(sorted))
+	terms := make([]*edwards.PublicKey, len(sorted))
+	for i, p := range sorted {
+		a := taggedHash(curve, tagKeyAggCoef, lBytes, p.Serialize())
+		coefs[i] = a
+		terms[i] = scalarMultPoint(curve, p, a)
+	}
+
+	return &AggregatedKey{
+		Q:       addPoints(curve, terms...),
+		Pubkeys: sorted,
+		Coefs:   coefs,
+	}, nil
+}
+
+// SignerState is the state a single signer carries between the two rounds
+// of a MuSig2 session for one message: its two secret nonces from round
+// one and its precomputed KeyAgg coefficient.
+type SignerState struct {
+	curve *edwards.TwistedEdwardsCurve
+	priv  *edwards.PrivateKey
+	agg   *AggregatedKey
+	coef  *big.Int
+
+	k1, k2 *big.Int
+	R1, R2 *edwards.PublicKey
+}
+
+// NewSession starts round one of a MuSig2 signing session for priv, whose
+// public key pub must be part of agg. nonce1 and nonce2 are caller-supplied
+// fresh randomness for the two secret nonces k_1, k_2; NewSession derives
+// their public commitments R_1 = k_1*G, R_2 = k_2*G, which must be
+// broadcast to the coordinator before round two can proceed.
+func NewSession(curve *edwards.TwistedEdwardsCurve, priv *edwards.PrivateKey, pub *edwards.PublicKey, agg *AggregatedKey, nonce1, nonce2 []byte) (*SignerState, error) {
+	coef, err := agg.CoefFor(pub)
+	if err != nil {
+		return nil, err
+	}
+
+	k1 := new(big.Int).Mod(new(big.Int).SetBytes(nonce1), curve.N)
+	k2 := new(big.Int).Mod(new(big.Int).SetBytes(nonce2), curve.N)
+
+	r1x, r1y := curve.ScalarBaseMult(k1.Bytes())
+	r2x, r2y := curve.ScalarBaseMult(k2.Bytes())
+
+	return &SignerState{
+		curve: curve,
+		priv:  priv,
+		agg:   agg,
+		coef:  coef,
+		k1:    k1,
+		k2:    k2,
+		R1:    edwards.NewPublicKey(curve, r1x, r1y),
+		R2:    edwards.NewPublicKey(curve, r2x, r2y),
+	}, nil
+}
+
+// PublicNonces returns the round-one public nonce commitments (R_1, R_2)
+// that must be shared with the other signers before round two.
+func (s *SignerState) PublicNonces() (*edwards.PublicKey, *edwards.PublicKey) {
+	return s.R1, s.R2
+}
+
+// AggregateNonces sums a set of signers' (R_1, R_2) pairs componentwise,
+// producing the two aggregated nonce points used to derive the session's
+// effective nonce R.
+func AggregateNonces(curve *edwards.TwistedEdwardsCurve, r1s, r2s []*edwards.PublicKey) (*edwards.PublicKey, *edwards.PublicKey, error) {
+	if len(r1s) == 0 || len(r1s) != len(r2s) {
+		return nil, nil, errors.New("musig2: mismatched nonce counts")
+	}
+	return addPoints(curve, r1s...), addPoints(curve, r2s...), nil
+}
+
+// nonceCoefficient computes b = H_non(aggR1 || aggR2 || Q || m).
+func nonceCoefficient(curve *edwards.TwistedEdwardsCurve, aggR1, aggR2, q *edwards.PublicKey, msg []byte) *big.Int {
+	return taggedHash(curve, tagNonceCoef, aggR1.Serialize(), aggR2.Serialize(), q.Serialize(), msg)
+}
+
+// sessionChallenge computes c = H(R||Q||m), the same plain (untagged)
+// SHA-512 challenge construction used for every other Schnorr signature in
+// this package (see adaptorChallenge in adaptor.go and challenge in
+// frost/sign.go). It deliberately does not use the tagged-hash
+// construction, unlike KeyAgg's internal L and a_i: the combined signature
+// CombinePartialSigs produces must verify with the standard edwards.Verify,
+// which reproduces this exact construction, not a domain-separated one.
+func sessionChallenge(r, q *edwards.PublicKey, msg []byte) *big.Int {
+	h := sha512.New()
+	h.Write(r.Serialize())
+	h.Write(q.Serialize())
+	h.Write(msg)
+
+	// Ed25519 reads the digest as a little-endian integer.
+	digest := h.Sum(nil)
+	for i, j := 0, len(digest)-1; i < j; i, j = i+1, j-1 {
+		digest[i], digest[j] = digest[j], digest[i]
+	}
+	return new(big.Int).SetBytes(digest)
+}
+
+// FinalNonce derives the session's effective nonce point R = aggR1 + b*aggR2
+// and the coefficient b used to compute it.
+func FinalNonce(curve *edwards.TwistedEdwardsCurve, aggR1, aggR2, q *edwards.PublicKey, msg []byte) (*edwards.PublicKey, *big.Int) {
+	b := nonceCoefficient(curve, aggR1, aggR2, q, msg)
+	bR2 := scalarMultPoint(curve, aggR2, b)
+	return addPoints(curve, aggR1, bR2), b
+}
+
+// Sign produces this signer's partial signature
+// s_i = k_1 + b*k_2 + c*a_i*x_i (mod N) for msg, given the round-two
+// aggregated nonces aggR1 and aggR2.
+func (s *SignerState) Sign(aggR1, aggR2 *edwards.PublicKey, msg []byte) (*big.Int, error) {
+	r, b := FinalNonce(s.curve, aggR1, aggR2, s.agg.Q, msg)
+	c := sessionChallenge(r, s.agg.Q, msg)
+	c.Mod(c, s.curve.N)
+
+	si := new(big.Int).Mul(b, s.k2)
+	si.Add(si, s.k1)
+
+	t := new(big.Int).Mul(c, s.coef)
+	t.Mul(t, s.priv.GetD())
+
+	si.Add(si, t)
+	return si.Mod(si, s.curve.N), nil
+}
+
+// CombinePartialSigs sums the partial signatures produced by Sign into the
+// final Ed25519 Schnorr signature, verifiable by the standard edwards.Verify
+// against the aggregated key Q.
+func CombinePartialSigs(curve *edwards.TwistedEdwardsCurve, aggR1, aggR2, q *edwards.PublicKey, msg []byte, partials []*big.Int) (*edwards.Signature, error) {
+	if len(partials) == 0 {
+		return nil, errors.New("musig2: at least one partial signature is required")
+	}
+
+	r, _ := FinalNonce(curve, aggR1, aggR2, q, msg)
+
+	s := new(big.Int)
+	for _, p := range partials {
+		s.Add(s, p)
+	}
+	s.Mod(s, curve.N)
+
+	var rArr [32]byte
+	copy(rArr[:], r.Serialize())
+	rEnc := edwards.EncodedBytesToBigInt(&rArr)
+	return edwards.NewSignature(rEnc, s), nil
+}
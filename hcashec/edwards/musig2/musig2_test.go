@@ -0,0 +1,177 @@
+// Copyright (c) 2017 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package musig2
+
+import (
+	"bytes"
+	"crypto/sha512"
+	"encoding/hex"
+	"math/big"
+	"sort"
+	"testing"
+
+	"github.com/tiaotiao00/hcashd/hcashec/edwards"
+)
+
+// Fixed test-vector inputs. Using hard-coded scalars rather than
+// crypto/rand keeps the protocol math (KeyAgg coefficients, nonce
+// aggregation, the final signature) reproducible run to run, and lets the
+// tests below independently recompute expected values rather than only
+// checking the implementation against itself.
+var (
+	vectorPriv1, _ = hex.DecodeString(
+		"0101010101010101010101010101010101010101010101010101010101010a")
+	vectorPriv2, _ = hex.DecodeString(
+		"0202020202020202020202020202020202020202020202020202020202020a")
+	vectorPriv3, _ = hex.DecodeString(
+		"0303030303030303030303030303030303030303030303030303030303030a")
+
+	vectorNonce1a, _ = hex.DecodeString(
+		"1111111111111111111111111111111111111111111111111111111111110a")
+	vectorNonce1b, _ = hex.DecodeString(
+		"1212121212121212121212121212121212121212121212121212121212120a")
+	vectorNonce2a, _ = hex.DecodeString(
+		"2121212121212121212121212121212121212121212121212121212121210a")
+	vectorNonce2b, _ = hex.DecodeString(
+		"2222222222222222222222222222222222222222222222222222222222220a")
+
+	vectorMsg, _ = hex.DecodeString(
+		"d04b98f48e8f8bcc15c6ae5ac050801cd6dcfd428fb5f9e65c4e16e7807340fa")
+)
+
+func fixedSigner(t *testing.T, curve *edwards.TwistedEdwardsCurve, scalar []byte) (*edwards.PrivateKey, *edwards.PublicKey) {
+	priv, pub, err := edwards.PrivKeyFromScalar(curve, scalar)
+	if err != nil {
+		t.Fatalf("PrivKeyFromScalar: %v", err)
+	}
+	return priv, pub
+}
+
+// referenceTaggedHash is an independent reimplementation of the tagged-hash
+// construction (SHA512(tagHash||tagHash||data...) mod N), built directly
+// from crypto/sha512 rather than calling back into this package, so it can
+// serve as a test vector for taggedHash instead of just checking taggedHash
+// against itself.
+func referenceTaggedHash(curve *edwards.TwistedEdwardsCurve, tag string, data ...[]byte) *big.Int {
+	tagHash := sha512.Sum512([]byte(tag))
+
+	h := sha512.New()
+	h.Write(tagHash[:])
+	h.Write(tagHash[:])
+	for _, d := range data {
+		h.Write(d)
+	}
+
+	e := new(big.Int).SetBytes(h.Sum(nil))
+	return e.Mod(e, curve.N)
+}
+
+// TestTaggedHashVector checks taggedHash against an independent
+// reimplementation of the tagged-hash construction for a fixed tag and
+// fixed data, pinning the exact byte layout (tagHash || tagHash || data...)
+// the rest of this package's security relies on.
+func TestTaggedHashVector(t *testing.T) {
+	curve := new(edwards.TwistedEdwardsCurve)
+	curve.InitParam25519()
+
+	got := taggedHash(curve, tagKeyAggCoef, []byte("vector-L"), []byte("vector-P"))
+	want := referenceTaggedHash(curve, tagKeyAggCoef, []byte("vector-L"), []byte("vector-P"))
+
+	if got.Cmp(want) != 0 {
+		t.Fatalf("taggedHash = %s, want %s (reference)", got.String(), want.String())
+	}
+}
+
+// TestKeyAggKnownVector runs KeyAgg over three fixed keys and checks both
+// that its output is order-independent and that every per-signer
+// coefficient matches one computed directly from the tagged-hash
+// construction, rather than only checking KeyAgg against itself.
+func TestKeyAggKnownVector(t *testing.T) {
+	curve := new(edwards.TwistedEdwardsCurve)
+	curve.InitParam25519()
+
+	_, pub1 := fixedSigner(t, curve, vectorPriv1)
+	_, pub2 := fixedSigner(t, curve, vectorPriv2)
+	_, pub3 := fixedSigner(t, curve, vectorPriv3)
+
+	agg1, err := KeyAgg(curve, []*edwards.PublicKey{pub1, pub2, pub3})
+	if err != nil {
+		t.Fatalf("KeyAgg: %v", err)
+	}
+	agg2, err := KeyAgg(curve, []*edwards.PublicKey{pub3, pub1, pub2})
+	if err != nil {
+		t.Fatalf("KeyAgg: %v", err)
+	}
+	if hex.EncodeToString(agg1.Q.Serialize()) != hex.EncodeToString(agg2.Q.Serialize()) {
+		t.Fatal("KeyAgg is not invariant under reordering of the input keys")
+	}
+
+	serialized := [][]byte{pub1.Serialize(), pub2.Serialize(), pub3.Serialize()}
+	sort.Slice(serialized, func(i, j int) bool {
+		return bytes.Compare(serialized[i], serialized[j]) < 0
+	})
+	wantL := referenceTaggedHash(curve, tagKeyAggList, serialized...)
+	lBytes := wantL.Bytes()
+
+	for i, pub := range agg1.Pubkeys {
+		want := referenceTaggedHash(curve, tagKeyAggCoef, lBytes, pub.Serialize())
+		if agg1.Coefs[i].Cmp(want) != 0 {
+			t.Fatalf("coefficient %d = %s, want %s (reference)", i, agg1.Coefs[i].String(), want.String())
+		}
+	}
+}
+
+// TestMuSig2TwoOfTwoVector runs a full two-round MuSig2 session between two
+// signers over fixed keys, nonces and message, and checks that the
+// combined signature verifies against the aggregated key with the
+// standard edwards.Verify.
+func TestMuSig2TwoOfTwoVector(t *testing.T) {
+	curve := new(edwards.TwistedEdwardsCurve)
+	curve.InitParam25519()
+
+	priv1, pub1 := fixedSigner(t, curve, vectorPriv1)
+	priv2, pub2 := fixedSigner(t, curve, vectorPriv2)
+
+	agg, err := KeyAgg(curve, []*edwards.PublicKey{pub1, pub2})
+	if err != nil {
+		t.Fatalf("KeyAgg: %v", err)
+	}
+
+	session1, err := NewSession(curve, priv1, pub1, agg, vectorNonce1a, vectorNonce1b)
+	if err != nil {
+		t.Fatalf("NewSession(1): %v", err)
+	}
+	session2, err := NewSession(curve, priv2, pub2, agg, vectorNonce2a, vectorNonce2b)
+	if err != nil {
+		t.Fatalf("NewSession(2): %v", err)
+	}
+
+	r1a, r2a := session1.PublicNonces()
+	r1b, r2b := session2.PublicNonces()
+
+	aggR1, aggR2, err := AggregateNonces(curve,
+		[]*edwards.PublicKey{r1a, r1b}, []*edwards.PublicKey{r2a, r2b})
+	if err != nil {
+		t.Fatalf("AggregateNonces: %v", err)
+	}
+
+	s1, err := session1.Sign(aggR1, aggR2, vectorMsg)
+	if err != nil {
+		t.Fatalf("Sign(1): %v", err)
+	}
+	s2, err := session2.Sign(aggR1, aggR2, vectorMsg)
+	if err != nil {
+		t.Fatalf("Sign(2): %v", err)
+	}
+
+	sig, err := CombinePartialSigs(curve, aggR1, aggR2, agg.Q, vectorMsg, []*big.Int{s1, s2})
+	if err != nil {
+		t.Fatalf("CombinePartialSigs: %v", err)
+	}
+
+	if !edwards.Verify(agg.Q, vectorMsg, sig.GetR(), sig.GetS()) {
+		t.Fatal("failed to verify combined MuSig2 signature")
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright (c) 2017 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package edwards
+
+import (
+	"crypto/sha512"
+	"errors"
+	"math/big"
+)
+
+// recoverableChallenge computes c = H(R||m), the alternate challenge used
+// by SignRecoverable. Ordinary Schnorr signatures in this package bind the
+// challenge to the signer's public key (c = H(R||P||m)), which makes
+// recovering P from (R, s) alone circular: P is needed to compute c, and c
+// is needed to solve for P. Leaving P out of the challenge breaks that
+// circularity at the cost of a slightly weaker signature (the signer no
+// longer explicitly commits to which key it's signing with), which is an
+// acceptable trade-off for callers that opt into recoverable signatures.
+func recoverableChallenge(curve *TwistedEdwardsCurve, r *PublicKey, msg []byte) *big.Int {
+	h := sha512.New()
+	h.Write(r.Serialize())
+	h.Write(msg)
+	e := new(big.Int).SetBytes(h.Sum(nil))
+	return e.Mod(e, curve.N)
+}
+
+// SignRecoverable signs msg with priv using the alternate challenge
+// c = H(R||m), producing a signature whose signer can later be recovered
+// with Recover. Ordinary signatures produced by Sign are not recoverable.
+func SignRecoverable(curve *TwistedEdwardsCurve, priv *PrivateKey, nonce []byte, msg []byte) (*Signature, error) {
+	k := new(big.Int).Mod(new(big.Int).SetBytes(nonce), curve.N)
+	if k.Sign() == 0 {
+		return nil, errors.New("edwards: nonce reduces to zero")
+	}
+
+	rx, ry := curve.ScalarBaseMult(k.Bytes())
+	r := NewPublicKey(curve, rx, ry)
+
+	c := recoverableChallenge(curve, r, msg)
+
+	s := new(big.Int).Mul(c, priv.GetD())
+	s.Add(s, k)
+	s.Mod(s, curve.N)
+
+	rEnc := EncodedBytesToBigInt(copyBytes(r.Serialize()))
+	return NewSignature(rEnc, s), nil
+}
+
+// Recover computes the public key P that made sig a valid SignRecoverable
+// signature over msg, via P = c^-1 * (sG - R) with c = H(R||m).
+func Recover(curve *TwistedEdwardsCurve, sig *Signature, msg []byte) (*PublicKey, error) {
+	if sig == nil {
+		return nil, errors.New("edwards: signature must not be nil")
+	}
+
+	rBytes := BigIntToEncodedBytes(sig.GetR())
+	r, err := ParsePubKey(curve, rBytes[:])
+	if err != nil {
+		return nil, err
+	}
+
+	c := recoverableChallenge(curve, r, msg)
+	cInv := new(big.Int).ModInverse(c, curve.N)
+	if cInv == nil {
+		return nil, errors.New("edwards: challenge is not invertible mod N")
+	}
+
+	sx, sy := curve.ScalarBaseMult(sig.GetS().Bytes())
+	sG := NewPublicKey(curve, sx, sy)
+
+	diff := CombinePubkeys(curve, []*PublicKey{sG, negatePoint(curve, r)})
+	if diff == nil {
+		return nil, errors.New("edwards: failed to compute sG - R")
+	}
+
+	px, py := curve.ScalarMult(diff.GetX(), diff.GetY(), cInv.Bytes())
+	return NewPublicKey(curve, px, py), nil
+}
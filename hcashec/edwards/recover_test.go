@@ -0,0 +1,90 @@
+// Copyright (c) 2017 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package edwards
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+)
+
+// TestSignRecoverableAndRecover checks that Recover reconstructs the
+// signer's public key from a SignRecoverable signature alone.
+func TestSignRecoverableAndRecover(t *testing.T) {
+	curve := new(TwistedEdwardsCurve)
+	curve.InitParam25519()
+
+	msg, _ := hex.DecodeString(
+		"d04b98f48e8f8bcc15c6ae5ac050801cd6dcfd428fb5f9e65c4e16e7807340fa")
+
+	privScalar, _ := hex.DecodeString(
+		"0706050403020100ffeeddccbbaa99887766554433221100ffeeddccbbaa9988")
+	priv, pub, err := PrivKeyFromScalar(curve, privScalar)
+	if err != nil {
+		t.Fatalf("PrivKeyFromScalar: %v", err)
+	}
+
+	nonce, _ := hex.DecodeString(
+		"2122232425262730313233343536404142434445464a50515253545556575a")
+
+	sig, err := SignRecoverable(curve, priv, nonce, msg)
+	if err != nil {
+		t.Fatalf("SignRecoverable: %v", err)
+	}
+
+	recovered, err := Recover(curve, sig, msg)
+	if err != nil {
+		t.Fatalf("Recover: %v", err)
+	}
+
+	if hex.EncodeToString(recovered.Serialize()) != hex.EncodeToString(pub.Serialize()) {
+		t.Fatalf("Recover returned %x, want %x", recovered.Serialize(), pub.Serialize())
+	}
+}
+
+// TestBatchVerify checks that BatchVerify accepts a batch of valid
+// signatures and reports the correct index when one is corrupted.
+func TestBatchVerify(t *testing.T) {
+	curve := new(TwistedEdwardsCurve)
+	curve.InitParam25519()
+
+	const numSigs = 8
+	entries := make([]BatchEntry, numSigs)
+
+	for i := 0; i < numSigs; i++ {
+		var scalar [32]byte
+		scalar[0] = byte(i + 1)
+		priv, pub, err := PrivKeyFromScalar(curve, scalar[:])
+		if err != nil {
+			t.Fatalf("PrivKeyFromScalar(%d): %v", i, err)
+		}
+
+		msg := []byte{byte(i), 0xaa, 0xbb}
+
+		var nonce [32]byte
+		nonce[1] = byte(i + 1)
+		r, s, err := SignFromScalar(curve, priv, nonce[:], msg)
+		if err != nil {
+			t.Fatalf("SignFromScalar(%d): %v", i, err)
+		}
+
+		entries[i] = BatchEntry{Pub: pub, Msg: msg, R: r, S: s}
+	}
+
+	ok, bad := BatchVerify(curve, entries)
+	if !ok {
+		t.Fatalf("BatchVerify rejected a fully valid batch, bad=%v", bad)
+	}
+
+	// Corrupt one signature and make sure BatchVerify flags it.
+	entries[3].S.Add(entries[3].S, big.NewInt(1))
+	ok, bad = BatchVerify(curve, entries)
+	if ok {
+		t.Fatal("BatchVerify accepted a batch containing a corrupted signature")
+	}
+	if len(bad) != 1 || bad[0] != 3 {
+		t.Fatalf("BatchVerify bad indices = %v, want [3]", bad)
+	}
+}